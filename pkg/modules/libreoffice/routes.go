@@ -0,0 +1,342 @@
+package libreoffice
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/gotenberg/gotenberg/v7/pkg/gotenberg"
+	"github.com/gotenberg/gotenberg/v7/pkg/modules/api"
+	libreofficeapi "github.com/gotenberg/gotenberg/v7/pkg/modules/libreoffice/api"
+)
+
+// convertRoute returns an api.Route which can convert LibreOffice documents
+// to PDF.
+func convertRoute(libreOffice libreofficeapi.Uno, engine gotenberg.PDFEngine) api.Route {
+	return api.Route{
+		Method:      http.MethodPost,
+		Path:        "/forms/libreoffice/convert",
+		IsMultipart: true,
+		Handler: func(c echo.Context) error {
+			ctx := c.Get("context").(*api.Context)
+
+			var (
+				inputPaths         []string
+				landscape          bool
+				nativePageRanges   string
+				nativePdfA1aFormat bool
+				nativePdfFormat    string
+				exportBookmarks    bool
+				merge              bool
+				pdfFormat          string
+				perFileOptionsRaw  string
+				continueOnError    bool
+			)
+
+			err := ctx.FormData().
+				MandatoryPaths(libreOffice.Extensions(), &inputPaths).
+				Bool("landscape", &landscape, false).
+				String("nativePageRanges", &nativePageRanges, "").
+				Bool("nativePdfA1aFormat", &nativePdfA1aFormat, false).
+				String("nativePdfFormat", &nativePdfFormat, "").
+				Bool("exportBookmarks", &exportBookmarks, false).
+				Bool("merge", &merge, false).
+				String("pdfFormat", &pdfFormat, "").
+				String("perFileOptions", &perFileOptionsRaw, "").
+				Bool("continueOnError", &continueOnError, false).
+				Validate()
+			if err != nil {
+				return fmt.Errorf("validate form data: %w", err)
+			}
+
+			if nativePdfA1aFormat && nativePdfFormat == "" {
+				// Deprecated - prefer nativePdfFormat, kept for backward compatibility.
+				nativePdfFormat = gotenberg.FormatPDFA1a
+			}
+
+			defaultOptions := libreofficeapi.Options{
+				Landscape:       landscape,
+				PageRanges:      nativePageRanges,
+				NativePdfFormat: nativePdfFormat,
+				ExportBookmarks: exportBookmarks,
+			}
+
+			optionsByPath, err := resolvePerFileOptions(perFileOptionsRaw, inputPaths, defaultOptions)
+			if err != nil {
+				return api.WrapError(
+					fmt.Errorf("resolve per-file options: %w", err),
+					api.NewSentinelHTTPError(http.StatusBadRequest, err.Error()),
+				)
+			}
+
+			// continueOnError only makes sense for the non-merge path: a
+			// failed input makes a merged output meaningless, so merging
+			// keeps the fail-fast behavior.
+			if continueOnError && !merge {
+				return convertWithManifest(ctx, libreOffice, engine, inputPaths, optionsByPath, pdfFormat)
+			}
+
+			outputPaths, err := convertInputs(ctx, libreOffice, optionsByPath, inputPaths)
+			if err != nil {
+				return err
+			}
+
+			if merge {
+				mergedPath := ctx.GeneratePath(".pdf")
+
+				err = engine.Merge(ctx, ctx.Log(), outputPaths, mergedPath)
+				if err != nil {
+					return fmt.Errorf("merge PDFs: %w", err)
+				}
+
+				mergedPath, err = convertToFormat(ctx, engine, pdfFormat, mergedPath)
+				if err != nil {
+					return err
+				}
+
+				err = ctx.AddOutputPaths(mergedPath)
+				if err != nil {
+					return fmt.Errorf("add output path: %w", err)
+				}
+
+				return nil
+			}
+
+			for i, outputPath := range outputPaths {
+				outputPath, err = convertToFormat(ctx, engine, pdfFormat, outputPath)
+				if err != nil {
+					return err
+				}
+
+				outputPaths[i] = outputPath
+			}
+
+			err = ctx.AddOutputPaths(outputPaths...)
+			if err != nil {
+				return fmt.Errorf("add output path: %w", err)
+			}
+
+			return nil
+		},
+	}
+}
+
+// convertInputs converts each input to PDF via LibreOffice, using the
+// options resolved for that specific input path.
+func convertInputs(ctx *api.Context, libreOffice libreofficeapi.Uno, optionsByPath map[string]libreofficeapi.Options, inputPaths []string) ([]string, error) {
+	outputPaths := make([]string, len(inputPaths))
+
+	for i, inputPath := range inputPaths {
+		outputPaths[i] = ctx.GeneratePath(".pdf")
+		options := optionsByPath[inputPath]
+
+		err := libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPaths[i], options)
+		if err != nil {
+			if errors.Is(err, libreofficeapi.ErrMalformedPageRanges) {
+				return nil, api.WrapError(
+					fmt.Errorf("convert to PDF: %w", err),
+					api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("A least one PDF could not be converted because the specified page ranges '%s' are malformed", options.PageRanges)),
+				)
+			}
+
+			return nil, fmt.Errorf("convert to PDF: %w", err)
+		}
+	}
+
+	return outputPaths, nil
+}
+
+// convertToFormat converts outputPath to pdfFormat via the PDF engine. If
+// pdfFormat is empty, outputPath is returned untouched.
+func convertToFormat(ctx *api.Context, engine gotenberg.PDFEngine, pdfFormat, outputPath string) (string, error) {
+	if pdfFormat == "" {
+		return outputPath, nil
+	}
+
+	convertedPath := ctx.GeneratePath(".pdf")
+
+	err := engine.Convert(ctx, ctx.Log(), pdfFormat, outputPath, convertedPath)
+	if err != nil {
+		if errors.Is(err, gotenberg.ErrPDFFormatNotAvailable) {
+			return "", api.WrapError(
+				fmt.Errorf("convert PDF to '%s': %w", pdfFormat, err),
+				api.NewSentinelHTTPError(http.StatusBadRequest, fmt.Sprintf("A least one PDF could not be converted to '%s' format", pdfFormat)),
+			)
+		}
+
+		return "", fmt.Errorf("convert PDF to '%s': %w", pdfFormat, err)
+	}
+
+	return convertedPath, nil
+}
+
+// perFileOptionsOverride is the JSON shape of a single entry of the
+// perFileOptions sidecar form field: a subset of libreofficeapi.Options
+// overriding the route's global options for one specific uploaded file.
+type perFileOptionsOverride struct {
+	Landscape       bool   `json:"landscape"`
+	PageRanges      string `json:"pageRanges"`
+	NativePdfFormat string `json:"nativePdfFormat"`
+	ExportBookmarks bool   `json:"exportBookmarks"`
+}
+
+// resolvePerFileOptions parses the perFileOptions sidecar form field, a JSON
+// object mapping an uploaded filename to its own conversion options, and
+// returns the libreofficeapi.Options to use for each input path. Filenames
+// absent from the map fall back to defaults built from the route's
+// top-level form fields. Filenames present in the map that do not match any
+// uploaded file make the conversion fail.
+func resolvePerFileOptions(raw string, inputPaths []string, defaults libreofficeapi.Options) (map[string]libreofficeapi.Options, error) {
+	resolved := make(map[string]libreofficeapi.Options, len(inputPaths))
+	pathByFilename := make(map[string]string, len(inputPaths))
+
+	for _, inputPath := range inputPaths {
+		resolved[inputPath] = defaults
+		pathByFilename[filepath.Base(inputPath)] = inputPath
+	}
+
+	if raw == "" {
+		return resolved, nil
+	}
+
+	var overrides map[string]perFileOptionsOverride
+
+	err := json.Unmarshal([]byte(raw), &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal perFileOptions: %w", err)
+	}
+
+	for filename, override := range overrides {
+		inputPath, ok := pathByFilename[filename]
+		if !ok {
+			return nil, fmt.Errorf("filename '%s' in perFileOptions does not match any uploaded file", filename)
+		}
+
+		resolved[inputPath] = libreofficeapi.Options{
+			Landscape:       override.Landscape,
+			PageRanges:      override.PageRanges,
+			NativePdfFormat: override.NativePdfFormat,
+			ExportBookmarks: override.ExportBookmarks,
+		}
+	}
+
+	return resolved, nil
+}
+
+// conversionStatus describes the outcome of converting a single input when
+// running in continueOnError mode. It is serialized as one entry of
+// manifest.json.
+type conversionStatus struct {
+	Filename   string `json:"filename"`
+	Status     string `json:"status"`
+	ErrorClass string `json:"errorClass,omitempty"`
+	ElapsedMs  int64  `json:"elapsedMs"`
+	Output     string `json:"output,omitempty"`
+}
+
+// classifyError reports the manifest error class for err, and whether it is
+// a client-side error (malformed input) as opposed to a backend failure.
+func classifyError(err error) (class string, isClientError bool) {
+	switch {
+	case errors.Is(err, libreofficeapi.ErrMalformedPageRanges):
+		return "ErrMalformedPageRanges", true
+	case errors.Is(err, gotenberg.ErrPDFFormatNotAvailable):
+		return "ErrPDFFormatNotAvailable", true
+	default:
+		return "generic", false
+	}
+}
+
+// convertWithManifest converts each input independently: a failed input is
+// recorded in manifest.json instead of aborting the others. The manifest is
+// added as an extra output alongside the successfully converted PDFs. The
+// response is only a client error if every input failed with a client-side
+// error; if at least one input succeeds, it returns nil so the caller gets a
+// 200 with the manifest.
+func convertWithManifest(ctx *api.Context, libreOffice libreofficeapi.Uno, engine gotenberg.PDFEngine, inputPaths []string, optionsByPath map[string]libreofficeapi.Options, pdfFormat string) error {
+	statuses := make([]conversionStatus, len(inputPaths))
+	successCount := 0
+	allClientErrors := true
+
+	for i, inputPath := range inputPaths {
+		filename := filepath.Base(inputPath)
+		start := time.Now()
+
+		outputPath := ctx.GeneratePath(".pdf")
+		options := optionsByPath[inputPath]
+
+		err := libreOffice.Pdf(ctx, ctx.Log(), inputPath, outputPath, options)
+		if err == nil {
+			outputPath, err = convertToFormat(ctx, engine, pdfFormat, outputPath)
+		}
+
+		if err != nil {
+			class, isClientError := classifyError(err)
+			allClientErrors = allClientErrors && isClientError
+
+			statuses[i] = conversionStatus{
+				Filename:   filename,
+				Status:     "error",
+				ErrorClass: class,
+				ElapsedMs:  time.Since(start).Milliseconds(),
+			}
+
+			continue
+		}
+
+		err = ctx.AddOutputPaths(outputPath)
+		if err != nil {
+			return fmt.Errorf("add output path: %w", err)
+		}
+
+		successCount++
+		statuses[i] = conversionStatus{
+			Filename:  filename,
+			Status:    "ok",
+			ElapsedMs: time.Since(start).Milliseconds(),
+			Output:    filepath.Base(outputPath),
+		}
+	}
+
+	manifest, err := json.Marshal(statuses)
+	if err != nil {
+		return fmt.Errorf("marshal manifest: %w", err)
+	}
+
+	// GeneratePath only hands out a fresh random name in the context's
+	// working directory; reuse that directory so manifest.json lands
+	// alongside the other outputs under a predictable name.
+	manifestPath := filepath.Join(filepath.Dir(ctx.GeneratePath(".json")), "manifest.json")
+
+	err = os.WriteFile(manifestPath, manifest, 0o600)
+	if err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	err = ctx.AddOutputPaths(manifestPath)
+	if err != nil {
+		return fmt.Errorf("add manifest output path: %w", err)
+	}
+
+	if successCount > 0 {
+		return nil
+	}
+
+	if allClientErrors {
+		return api.WrapError(
+			fmt.Errorf("convert to PDF: all %d input(s) failed", len(inputPaths)),
+			api.NewSentinelHTTPError(http.StatusBadRequest, "All inputs failed to convert, see manifest.json for details"),
+		)
+	}
+
+	return api.WrapError(
+		fmt.Errorf("convert to PDF: all %d input(s) failed", len(inputPaths)),
+		api.NewSentinelHTTPError(http.StatusBadGateway, "All inputs failed to convert, see manifest.json for details"),
+	)
+}
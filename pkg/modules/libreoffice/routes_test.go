@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"path/filepath"
 	"testing"
 
 	"github.com/labstack/echo/v4"
@@ -528,3 +529,259 @@ func TestConvertRoute(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertRoutePerFileOptions(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		ctx                    *api.ContextMock
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+		expectOptionsByInput   map[string]libreofficeapi.Options
+	}{
+		{
+			scenario: "mismatched filename",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"document.docx": "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"perFileOptions": {
+						`{"does-not-exist.docx":{"landscape":true}}`,
+					},
+				})
+				return ctx
+			}(),
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 0,
+		},
+		{
+			scenario: "partial coverage merged with defaults",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"spreadsheet.xlsx": "/spreadsheet.xlsx",
+					"document.docx":    "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"landscape": {
+						"false",
+					},
+					"perFileOptions": {
+						`{"spreadsheet.xlsx":{"landscape":true}}`,
+					},
+				})
+				return ctx
+			}(),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+			expectOptionsByInput: map[string]libreofficeapi.Options{
+				"/spreadsheet.xlsx": {Landscape: true},
+				"/document.docx":    {Landscape: false},
+			},
+		},
+		{
+			scenario: "all-specified success",
+			ctx: func() *api.ContextMock {
+				ctx := &api.ContextMock{Context: new(api.Context)}
+				ctx.SetFiles(map[string]string{
+					"spreadsheet.xlsx": "/spreadsheet.xlsx",
+					"document.docx":    "/document.docx",
+				})
+				ctx.SetValues(map[string][]string{
+					"perFileOptions": {
+						`{
+							"spreadsheet.xlsx": {"landscape":true,"pageRanges":"1-2"},
+							"document.docx": {"exportBookmarks":true}
+						}`,
+					},
+				})
+				return ctx
+			}(),
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2,
+			expectOptionsByInput: map[string]libreofficeapi.Options{
+				"/spreadsheet.xlsx": {Landscape: true, PageRanges: "1-2"},
+				"/document.docx":    {ExportBookmarks: true},
+			},
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tc.ctx.SetLogger(zap.NewNop())
+
+			receivedOptions := make(map[string]libreofficeapi.Options)
+
+			libreOffice := &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					receivedOptions[inputPath] = options
+					return nil
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx", ".xlsx"}
+				},
+			}
+
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", tc.ctx.Context)
+
+			err := convertRoute(libreOffice, nil).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HTTPError
+			isHTTPErr := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHTTPErr {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHTTPErr {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHTTPErr {
+				status, _ := httpErr.HTTPError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			if tc.expectOutputPathsCount != len(tc.ctx.OutputPaths()) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(tc.ctx.OutputPaths()))
+			}
+
+			for inputPath, expected := range tc.expectOptionsByInput {
+				if receivedOptions[inputPath] != expected {
+					t.Errorf("expected options %+v for '%s' but got %+v", expected, inputPath, receivedOptions[inputPath])
+				}
+			}
+		})
+	}
+}
+
+func TestConvertRouteContinueOnError(t *testing.T) {
+	for _, tc := range []struct {
+		scenario               string
+		failingInputPaths      map[string]error
+		expectError            bool
+		expectHttpError        bool
+		expectHttpStatus       int
+		expectOutputPathsCount int
+	}{
+		{
+			scenario: "mixed success/failure batch",
+			failingInputPaths: map[string]error{
+				"/document2.docx": errors.New("foo"),
+			},
+			expectError:            false,
+			expectHttpError:        false,
+			expectOutputPathsCount: 2, // One converted PDF, plus manifest.json.
+		},
+		{
+			scenario: "all-fail batch promotes to HTTP 502",
+			failingInputPaths: map[string]error{
+				"/document.docx":  errors.New("foo"),
+				"/document2.docx": errors.New("bar"),
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadGateway,
+			expectOutputPathsCount: 1, // manifest.json only.
+		},
+		{
+			scenario: "all-fail batch with only client errors stays HTTP 400",
+			failingInputPaths: map[string]error{
+				"/document.docx":  libreofficeapi.ErrMalformedPageRanges,
+				"/document2.docx": libreofficeapi.ErrMalformedPageRanges,
+			},
+			expectError:            true,
+			expectHttpError:        true,
+			expectHttpStatus:       http.StatusBadRequest,
+			expectOutputPathsCount: 1, // manifest.json only.
+		},
+	} {
+		t.Run(tc.scenario, func(t *testing.T) {
+			ctx := &api.ContextMock{Context: new(api.Context)}
+			ctx.SetFiles(map[string]string{
+				"document.docx":  "/document.docx",
+				"document2.docx": "/document2.docx",
+			})
+			ctx.SetValues(map[string][]string{
+				"continueOnError": {
+					"true",
+				},
+			})
+			ctx.SetLogger(zap.NewNop())
+
+			libreOffice := &libreofficeapi.ApiMock{
+				PdfMock: func(ctx context.Context, logger *zap.Logger, inputPath, outputPath string, options libreofficeapi.Options) error {
+					return tc.failingInputPaths[inputPath]
+				},
+				ExtensionsMock: func() []string {
+					return []string{".docx"}
+				},
+			}
+
+			c := echo.New().NewContext(nil, nil)
+			c.Set("context", ctx.Context)
+
+			err := convertRoute(libreOffice, nil).Handler(c)
+
+			if tc.expectError && err == nil {
+				t.Fatal("expected error but got none", err)
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("expected no error but got: %v", err)
+			}
+
+			var httpErr api.HTTPError
+			isHTTPErr := errors.As(err, &httpErr)
+
+			if tc.expectHttpError && !isHTTPErr {
+				t.Errorf("expected an HTTP error but got: %v", err)
+			}
+
+			if !tc.expectHttpError && isHTTPErr {
+				t.Errorf("expected no HTTP error but got one: %v", httpErr)
+			}
+
+			if err != nil && tc.expectHttpError && isHTTPErr {
+				status, _ := httpErr.HTTPError()
+				if status != tc.expectHttpStatus {
+					t.Errorf("expected %d as HTTP status code but got %d", tc.expectHttpStatus, status)
+				}
+			}
+
+			outputPaths := ctx.OutputPaths()
+
+			if tc.expectOutputPathsCount != len(outputPaths) {
+				t.Errorf("expected %d output paths but got %d", tc.expectOutputPathsCount, len(outputPaths))
+			}
+
+			manifestFound := false
+			for _, path := range outputPaths {
+				if filepath.Base(path) == "manifest.json" {
+					manifestFound = true
+					break
+				}
+			}
+
+			if !manifestFound {
+				t.Error("expected manifest.json to be part of the output paths")
+			}
+		})
+	}
+}